@@ -0,0 +1,67 @@
+// Package archive persists the merged hour-by-hour forecast/telemetry data
+// a strip was rendered from, so a render can later be reconstructed exactly
+// as it appeared at some past moment instead of only ever showing "now".
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nicpottier/weatherstrip/fetch"
+	"github.com/nicpottier/weatherstrip/forecast"
+)
+
+// Store archives merged forecast snapshots to an ObjectStore (the same
+// interface fetch's S3 cache tier uses), keyed by strip and the hour the
+// snapshot was built for. Snapshots are written as JSON, same as the
+// config file, rather than Parquet, since nothing else in weatherstrip
+// needs columnar access to them.
+type Store struct {
+	store fetch.ObjectStore
+}
+
+// NewStore returns a Store that archives to and reconstructs from store.
+func NewStore(store fetch.ObjectStore) *Store {
+	return &Store{store: store}
+}
+
+// Save archives merged under strip/now. Callers should treat a failure as
+// best-effort, the same way the fetch cache treats a failed S3 write: a
+// colder next time-travel request, not a failed render.
+func (s *Store) Save(ctx context.Context, strip string, now time.Time, merged map[time.Time]*forecast.HourForecast) error {
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(ctx, key(strip, now), data)
+}
+
+// Load reconstructs the merged snapshot archived for strip at the hour
+// `at` falls in. It returns an error if no snapshot was ever archived for
+// that hour, so callers can fall back to reconstructing what they can from
+// a provider's own historical archive instead.
+func (s *Store) Load(ctx context.Context, strip string, at time.Time) (map[time.Time]*forecast.HourForecast, error) {
+	data, ok, err := s.store.Get(ctx, key(strip, at))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no archive for %s at %s", strip, at.Format(time.RFC3339))
+	}
+
+	merged := make(map[time.Time]*forecast.HourForecast)
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// key is the object key a strip's snapshot for the hour `at` falls in is
+// archived under.
+func key(strip string, at time.Time) string {
+	return fmt.Sprintf("archive/%s/%s.json", strip, at.UTC().Truncate(time.Hour).Format("2006-01-02T15"))
+}
@@ -0,0 +1,98 @@
+// Package config loads the named "strips" a weatherstrip deployment can
+// serve from a JSON file, so one Lambda can render Stevens, Snoqualmie,
+// Baker, etc. instead of baking one station's settings into the binary.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+)
+
+// Palette is the set of colors a strip draws with, as "#rrggbb" hex
+// strings so it reads naturally in JSON.
+type Palette struct {
+	Main       string `json:"main"`
+	Sun        string `json:"sun"`
+	Background string `json:"background"`
+	Time       string `json:"time"`
+	Cold       string `json:"cold"`
+	Hot        string `json:"hot"`
+	Night      string `json:"night"`
+}
+
+// Strip is everything weatherstrip needs to render one named strip: where
+// to fetch telemetry and forecast data, the elevation/temperature
+// thresholds that decide rain vs. snow, and how big to draw it.
+type Strip struct {
+	Name string `json:"name"`
+
+	TelemetryURL string `json:"telemetry_url"`
+	// Gridpoint documents the NWS gridpoint this strip falls in (e.g.
+	// "SEW/164,65"); the NWS provider resolves it from Lat/Lon itself, so
+	// this is informational unless a future provider wants an override.
+	Gridpoint string  `json:"gridpoint,omitempty"`
+	Lat       float64 `json:"lat"`
+	Lon       float64 `json:"lon"`
+
+	ElevationM float64 `json:"elevation_m"`
+	SnowLevelM float64 `json:"snow_level_m"`
+	ColdTempF  float64 `json:"cold_temp_f"`
+	HotTempF   float64 `json:"hot_temp_f"`
+
+	GridWidth  int `json:"grid_width,omitempty"`
+	GridHeight int `json:"grid_height,omitempty"`
+
+	Palette Palette `json:"palette"`
+}
+
+// Config is the top-level shape of a WEATHERSTRIP_CONFIG file: a set of
+// strips, keyed by the name used in "/{stripName}.png" requests.
+type Config struct {
+	Strips map[string]Strip `json:"strips"`
+}
+
+// Load reads and parses a Config from path.
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Strip looks up a strip by name.
+func (c *Config) Strip(name string) (*Strip, error) {
+	strip, ok := c.Strips[name]
+	if !ok {
+		return nil, fmt.Errorf("no strip configured with name %q", name)
+	}
+	return &strip, nil
+}
+
+// ParseColor parses a "#rrggbb" hex string into a color.RGBA. An empty
+// string parses as fully transparent black, so callers can tell an unset
+// palette field apart from an explicit color.
+func ParseColor(hex string) (*color.RGBA, error) {
+	if hex == "" {
+		return &color.RGBA{}, nil
+	}
+
+	if len(hex) != 7 || hex[0] != '#' {
+		return nil, fmt.Errorf("invalid color %q, expected #rrggbb", hex)
+	}
+
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(hex[1:], "%02x%02x%02x", &r, &g, &b); err != nil {
+		return nil, fmt.Errorf("invalid color %q: %w", hex, err)
+	}
+
+	return &color.RGBA{R: r, G: g, B: b, A: 255}, nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// Store caches a parsed Config, reloading it only when the underlying
+// file's mtime changes. Lambda invocations within the same warm container
+// share a Store and so skip re-parsing on every request.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	config  *Config
+}
+
+// NewStore returns a Store that loads its Config from path on first use.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Get returns the current Config, reloading from disk if the file's mtime
+// has changed since it was last parsed.
+func (s *Store) Get() (*Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config != nil && info.ModTime().Equal(s.modTime) {
+		return s.config, nil
+	}
+
+	cfg, err := Load(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.config = cfg
+	s.modTime = info.ModTime()
+
+	return s.config, nil
+}
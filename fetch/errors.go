@@ -0,0 +1,16 @@
+package fetch
+
+import "fmt"
+
+type statusError struct {
+	status int
+	url    string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d fetching %s", e.status, e.url)
+}
+
+func errStatus(status int, url string) error {
+	return &statusError{status: status, url: url}
+}
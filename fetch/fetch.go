@@ -0,0 +1,195 @@
+// Package fetch wraps outbound HTTP calls to upstream weather APIs with an
+// LRU + optional S3 cache, so repeated Lambda invocations within a host's
+// TTL window skip the network entirely, and a fetch failure falls back to
+// the last good payload instead of failing the whole request.
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Fetcher retrieves the bytes at a URL.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+// entry is what's cached per URL: the payload plus enough HTTP
+// conditional-request metadata to revalidate it cheaply, and when it was
+// last known good, so stale entries can still serve stale-while-revalidate.
+type entry struct {
+	data         []byte
+	etag         string
+	lastModified string
+	expiresAt    time.Time
+	fetchedAt    time.Time
+}
+
+func (e *entry) fresh(now time.Time) bool {
+	return now.Before(e.expiresAt)
+}
+
+// storeEnvelope is how an entry is persisted to the second-tier
+// ObjectStore: the payload plus its TTL and conditional-request metadata,
+// so a cold Lambda start restoring from S3 can serve a fresh hit straight
+// from the envelope instead of always revalidating over the network.
+type storeEnvelope struct {
+	Data         []byte    `json:"data"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// CachingFetcher fetches URLs over HTTP, caching responses in an in-memory
+// LRU and, if Store is set, in S3 as a second tier that survives cold
+// starts. Responses are revalidated with If-None-Match/If-Modified-Since
+// once their TTL expires, and a fetch error serves the last good payload
+// if one is cached rather than failing outright.
+type CachingFetcher struct {
+	Client *http.Client
+	Store  ObjectStore
+
+	lru *lru
+}
+
+// NewCachingFetcher returns a CachingFetcher with an in-memory LRU of
+// maxEntries URLs. store may be nil to disable the S3 tier.
+func NewCachingFetcher(maxEntries int, store ObjectStore) *CachingFetcher {
+	return &CachingFetcher{
+		Client: http.DefaultClient,
+		Store:  store,
+		lru:    newLRU(maxEntries),
+	}
+}
+
+// Fetch returns the bytes at url, using the cache when the TTL for url's
+// host hasn't expired, revalidating with conditional headers when it has,
+// and falling back to the last cached payload if the upstream request
+// fails or returns an error status.
+func (f *CachingFetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
+	now := time.Now()
+
+	cached := f.load(ctx, url)
+	if cached != nil && cached.fresh(now) {
+		return cached.data, nil
+	}
+
+	fresh, err := f.revalidate(ctx, url, cached)
+	if err != nil {
+		if cached != nil {
+			// stale-while-revalidate: better to serve what we have than
+			// fail the whole request because an upstream hiccuped
+			return cached.data, nil
+		}
+		return nil, err
+	}
+
+	return fresh.data, nil
+}
+
+func (f *CachingFetcher) load(ctx context.Context, url string) *entry {
+	if e, ok := f.lru.get(url); ok {
+		return e
+	}
+
+	if f.Store == nil {
+		return nil
+	}
+
+	raw, ok, err := f.Store.Get(ctx, storeKey(url))
+	if err != nil || !ok {
+		return nil
+	}
+
+	env := &storeEnvelope{}
+	if err := json.Unmarshal(raw, env); err != nil {
+		return nil
+	}
+
+	e := &entry{
+		data:         env.Data,
+		etag:         env.ETag,
+		lastModified: env.LastModified,
+		expiresAt:    env.ExpiresAt,
+		fetchedAt:    env.FetchedAt,
+	}
+	f.lru.set(url, e)
+	return e
+}
+
+func (f *CachingFetcher) revalidate(ctx context.Context, url string, cached *entry) (*entry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	now := time.Now()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		cached.expiresAt = now.Add(ttlForURL(url))
+		cached.fetchedAt = now
+		f.store(ctx, url, cached)
+		return cached, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errStatus(resp.StatusCode, url)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &entry{
+		data:         body,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		expiresAt:    now.Add(cacheLifetime(resp.Header, url, now)),
+		fetchedAt:    now,
+	}
+	f.store(ctx, url, e)
+
+	return e, nil
+}
+
+func (f *CachingFetcher) store(ctx context.Context, url string, e *entry) {
+	f.lru.set(url, e)
+
+	if f.Store != nil {
+		raw, err := json.Marshal(storeEnvelope{
+			Data:         e.data,
+			ETag:         e.etag,
+			LastModified: e.lastModified,
+			ExpiresAt:    e.expiresAt,
+			FetchedAt:    e.fetchedAt,
+		})
+		if err == nil {
+			// best-effort; a failed S3 write just means a colder next cold-start
+			_ = f.Store.Put(ctx, storeKey(url), raw)
+		}
+	}
+}
+
+func storeKey(url string) string {
+	return url
+}
@@ -0,0 +1,70 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// ObjectStore is the second-tier cache behind the in-memory LRU; S3Store is
+// the production implementation, but callers can fake this out in tests.
+type ObjectStore interface {
+	Get(ctx context.Context, key string) (data []byte, ok bool, err error)
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// S3Store caches fetched payloads in an S3 bucket so they survive a cold
+// Lambda start, not just a warm one.
+type S3Store struct {
+	client s3iface.S3API
+	bucket string
+}
+
+// NewS3Store returns an S3Store backed by bucket, using the default AWS
+// session (region/credentials from the Lambda execution environment).
+func NewS3Store(bucket string) (*S3Store, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Store{client: s3.New(sess), bucket: bucket}, nil
+}
+
+// Get implements ObjectStore.
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	defer out.Body.Close()
+
+	data, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+// Put implements ObjectStore.
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
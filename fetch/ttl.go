@@ -0,0 +1,63 @@
+package fetch
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hostTTLs overrides the default TTL for hosts we know the refresh cadence
+// of: NWS gridpoints update roughly hourly, station telemetry every ten
+// minutes.
+var hostTTLs = map[string]time.Duration{
+	"api.weather.gov":            time.Hour,
+	"www.nwac.us":                10 * time.Minute,
+	"api.snowobs.com":            10 * time.Minute,
+	"api.open-meteo.com":         time.Hour,
+	"archive-api.open-meteo.com": time.Hour,
+	"api.openweathermap.org":     time.Hour,
+}
+
+const defaultTTL = 15 * time.Minute
+
+// ttlForURL is the TTL to use when a response doesn't carry its own
+// Cache-Control/Expires, based on the per-host overrides above.
+func ttlForURL(rawURL string) time.Duration {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return defaultTTL
+	}
+
+	if ttl, ok := hostTTLs[u.Host]; ok {
+		return ttl
+	}
+
+	return defaultTTL
+}
+
+// cacheLifetime honors a response's own Cache-Control max-age or Expires
+// header when present, falling back to the per-host TTL otherwise.
+func cacheLifetime(header http.Header, rawURL string, now time.Time) time.Duration {
+	if cc := header.Get("Cache-Control"); cc != "" {
+		for _, directive := range strings.Split(cc, ",") {
+			directive = strings.TrimSpace(directive)
+			if strings.HasPrefix(directive, "max-age=") {
+				if secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+					return time.Duration(secs) * time.Second
+				}
+			}
+		}
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		if t, err := http.ParseTime(expires); err == nil {
+			if d := t.Sub(now); d > 0 {
+				return d
+			}
+		}
+	}
+
+	return ttlForURL(rawURL)
+}
@@ -0,0 +1,46 @@
+package forecast
+
+// ToFahrenheit converts a Celsius temperature to Fahrenheit.
+func ToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// ToCelsius converts a Fahrenheit temperature to Celsius.
+func ToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
+
+// MMToInch converts millimeters to inches.
+func MMToInch(mm float64) float64 {
+	return mm / 25.4
+}
+
+// InchToMM converts inches to millimeters.
+func InchToMM(in float64) float64 {
+	return in * 25.4
+}
+
+// MetersToFeet converts meters to feet.
+func MetersToFeet(m float64) float64 {
+	return m * 3.28084
+}
+
+// FeetToMeters converts feet to meters.
+func FeetToMeters(ft float64) float64 {
+	return ft / 3.28084
+}
+
+// standardLapseRateCPerM is the International Standard Atmosphere's average
+// temperature lapse rate, used by EstimateSnowLevelM to approximate a
+// freezing level from a single surface reading.
+const standardLapseRateCPerM = 0.0065
+
+// EstimateSnowLevelM approximates the elevation, in meters, at which
+// surfaceTempC crosses freezing above a station at surfaceElevationM,
+// assuming a standard atmospheric lapse rate. It's a rough stand-in for
+// providers that don't expose an actual freezing level (OpenWeatherMap's
+// One Call API doesn't); prefer a provider-reported value when one is
+// available, as NWS and Open-Meteo both report.
+func EstimateSnowLevelM(surfaceTempC, surfaceElevationM float64) float64 {
+	return surfaceElevationM + surfaceTempC/standardLapseRateCPerM
+}
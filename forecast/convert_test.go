@@ -0,0 +1,77 @@
+package forecast
+
+import "testing"
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"ToFahrenheit freezing", ToFahrenheit(0), 32},
+		{"ToFahrenheit boiling", ToFahrenheit(100), 212},
+		{"ToCelsius freezing", ToCelsius(32), 0},
+		{"ToCelsius boiling", ToCelsius(212), 100},
+		{"MMToInch one inch", MMToInch(25.4), 1},
+		{"InchToMM one inch", InchToMM(1), 25.4},
+		{"MetersToFeet one meter", MetersToFeet(1), 3.28084},
+		{"FeetToMeters one meter worth", FeetToMeters(3.28084), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !almostEqual(tt.got, tt.want) {
+				t.Errorf("got %f, want %f", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateSnowLevelM(t *testing.T) {
+	tests := []struct {
+		name              string
+		surfaceTempC      float64
+		surfaceElevationM float64
+		want              float64
+	}{
+		{"freezing at the surface", 0, 1200, 1200},
+		{"above freezing at sea level", 5, 0, 5 / standardLapseRateCPerM},
+		{"below freezing above the surface", -6.5, 1200, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateSnowLevelM(tt.surfaceTempC, tt.surfaceElevationM)
+			if !almostEqual(got, tt.want) {
+				t.Errorf("got %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+// almostEqual reports whether a and b are close enough to be considered
+// equal for float-conversion test assertions.
+func almostEqual(a, b float64) bool {
+	const epsilon = 1e-6
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff < epsilon
+}
+
+// assertHourForecast fails t if got and want differ in any field, float
+// fields compared with almostEqual tolerance.
+func assertHourForecast(t *testing.T, i int, got, want HourForecast) {
+	t.Helper()
+
+	if !got.Hour.Equal(want.Hour) ||
+		!almostEqual(got.PredictedSnow, want.PredictedSnow) ||
+		!almostEqual(got.PredictedSnowLevel, want.PredictedSnowLevel) ||
+		!almostEqual(got.PredictedTemp, want.PredictedTemp) ||
+		!almostEqual(got.ActualSnow, want.ActualSnow) ||
+		!almostEqual(got.ActualTemp, want.ActualTemp) ||
+		!almostEqual(got.ActualPrecip, want.ActualPrecip) {
+		t.Errorf("hour %d: got %+v, want %+v", i, got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package forecast
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/nicpottier/weatherstrip/fetch"
+)
+
+// fetcher is the shared, cached HTTP fetcher all providers' getURL calls go
+// through, so repeated Lambda invocations within a host's TTL window skip
+// the network entirely. It's built once, from env, on first use.
+var (
+	fetcherOnce sync.Once
+	fetcher     fetch.Fetcher
+	fetcherErr  error
+)
+
+// fetcherFromEnv builds the shared CachingFetcher, sized by FETCH_CACHE_SIZE
+// (default 64 URLs) and backed by an S3 bucket when FETCH_CACHE_BUCKET is
+// set, so cached payloads survive a cold Lambda start too.
+func fetcherFromEnv() (fetch.Fetcher, error) {
+	maxEntries := 64
+	if v := os.Getenv("FETCH_CACHE_SIZE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FETCH_CACHE_SIZE: %w", err)
+		}
+		maxEntries = parsed
+	}
+
+	var store fetch.ObjectStore
+	if bucket := os.Getenv("FETCH_CACHE_BUCKET"); bucket != "" {
+		s3Store, err := fetch.NewS3Store(bucket)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring FETCH_CACHE_BUCKET: %w", err)
+		}
+		store = s3Store
+	}
+
+	return fetch.NewCachingFetcher(maxEntries, store), nil
+}
+
+// getURL fetches url through the shared cache, so NWS/Open-Meteo/
+// OpenWeatherMap requests alike benefit from it.
+func getURL(ctx context.Context, url string) ([]byte, error) {
+	fetcherOnce.Do(func() {
+		fetcher, fetcherErr = fetcherFromEnv()
+	})
+	if fetcherErr != nil {
+		return nil, fetcherErr
+	}
+
+	return fetcher.Fetch(ctx, url)
+}
@@ -0,0 +1,93 @@
+// Package forecast normalizes weather data from multiple upstream providers
+// (NWS, Open-Meteo, OpenWeatherMap) into a single []HourForecast timeline
+// that the rest of weatherstrip renders without caring where the data came
+// from.
+package forecast
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// HourForecast is a single hour of observed and/or predicted weather,
+// normalized to the units the renderer expects (Fahrenheit, inches, and
+// meters for PredictedSnowLevel).
+type HourForecast struct {
+	Hour time.Time `json:"hour"`
+
+	PredictedSnow      float64 `json:"predicted_snow,omitempty"`
+	PredictedSnowLevel float64 `json:"predicted_snow_level,omitempty"`
+	PredictedTemp      float64 `json:"predicted_temp,omitempty"`
+
+	ActualSnow   float64 `json:"actual_snow,omitempty"`
+	ActualTemp   float64 `json:"actual_temp,omitempty"`
+	ActualPrecip float64 `json:"actual_precip,omitempty"`
+}
+
+// Provider fetches normalized forecast data from a single upstream weather
+// API. FetchPast returns actual observations for a station between from and
+// to, FetchFuture returns predicted conditions for a lat/lon going forward.
+// elevationM is the station's elevation, passed through for providers that
+// don't expose a freezing level/snow level directly and have to estimate
+// one from surface temperature instead (see EstimateSnowLevelM).
+type Provider interface {
+	FetchPast(ctx context.Context, station string, from, to time.Time) ([]HourForecast, error)
+	FetchFuture(ctx context.Context, lat, lon, elevationM float64) ([]HourForecast, error)
+}
+
+// NewProviderFromEnv builds a Provider based on the FORECAST_PROVIDER env
+// var ("nws", "open-meteo" or "openweathermap"), defaulting to "nws" to
+// preserve existing behavior.
+func NewProviderFromEnv() (Provider, error) {
+	switch os.Getenv("FORECAST_PROVIDER") {
+	case "", "nws":
+		return NewNWSProvider(), nil
+	case "open-meteo":
+		return NewOpenMeteoProvider(), nil
+	case "openweathermap":
+		apiKey := os.Getenv("OPENWEATHERMAP_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("OPENWEATHERMAP_API_KEY must be set when FORECAST_PROVIDER=openweathermap")
+		}
+		return NewOpenWeatherMapProvider(apiKey), nil
+	}
+
+	return nil, fmt.Errorf("unknown FORECAST_PROVIDER: %s", os.Getenv("FORECAST_PROVIDER"))
+}
+
+// Merge folds a slice of normalized forecasts into an hour-keyed map,
+// overwriting only the fields present on each incoming forecast so that
+// past and future data for the same hour (e.g. a forecast later confirmed
+// by telemetry) don't clobber each other.
+func Merge(merged map[time.Time]*HourForecast, hours []HourForecast) {
+	for i := range hours {
+		h := hours[i]
+		present := merged[h.Hour]
+		if present == nil {
+			copied := h
+			merged[h.Hour] = &copied
+			continue
+		}
+
+		if h.PredictedSnow != 0 {
+			present.PredictedSnow = h.PredictedSnow
+		}
+		if h.PredictedSnowLevel != 0 {
+			present.PredictedSnowLevel = h.PredictedSnowLevel
+		}
+		if h.PredictedTemp != 0 {
+			present.PredictedTemp = h.PredictedTemp
+		}
+		if h.ActualSnow != 0 {
+			present.ActualSnow = h.ActualSnow
+		}
+		if h.ActualTemp != 0 {
+			present.ActualTemp = h.ActualTemp
+		}
+		if h.ActualPrecip != 0 {
+			present.ActualPrecip = h.ActualPrecip
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package forecast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// nwsHourRangeRegex pulls the hour count out of an ISO8601 duration like
+// "PT3H" on a gridpoint forecast value.
+var nwsHourRangeRegex = regexp.MustCompile(`PT(\d+)H`)
+
+// pointsResponse is the subset of api.weather.gov/points/{lat},{lon} we
+// need to find the gridpoint forecast endpoint for a location.
+type pointsResponse struct {
+	Properties struct {
+		GridID   string `json:"gridId"`
+		GridX    int    `json:"gridX"`
+		GridY    int    `json:"gridY"`
+		Forecast string `json:"forecastGridData"`
+	} `json:"properties"`
+}
+
+// gridpointURL resolves lat/lon to the NWS gridpoint forecast endpoint that
+// covers it, via the /points lookup.
+func gridpointURL(ctx context.Context, lat, lon float64) (string, error) {
+	data, err := getURL(ctx, fmt.Sprintf("https://api.weather.gov/points/%f,%f", lat, lon))
+	if err != nil {
+		return "", errors.Wrap(err, "error resolving NWS gridpoint")
+	}
+
+	points := &pointsResponse{}
+	if err := json.Unmarshal(data, points); err != nil {
+		return "", errors.Wrap(err, "error parsing NWS points response")
+	}
+
+	if points.Properties.Forecast != "" {
+		return points.Properties.Forecast, nil
+	}
+
+	return fmt.Sprintf("https://api.weather.gov/gridpoints/%s/%d,%d", points.Properties.GridID, points.Properties.GridX, points.Properties.GridY), nil
+}
+
+// NWSProvider fetches future conditions from the NWS gridpoint forecast API
+// and past conditions from a NWAC/snowobs station telemetry feed.
+type NWSProvider struct{}
+
+// NewNWSProvider returns a Provider backed by api.weather.gov gridpoints.
+func NewNWSProvider() *NWSProvider {
+	return &NWSProvider{}
+}
+
+// gridpointForecast mirrors the subset of properties we care about from a
+// NWS gridpoint forecast response.
+type gridpointForecast struct {
+	Properties struct {
+		Temperature struct {
+			Values []struct {
+				Time  string  `json:"validTime"`
+				Value float64 `json:"value"`
+			} `json:"values"`
+		} `json:"temperature"`
+		SnowFallAmount struct {
+			Values []struct {
+				Time  string  `json:"validTime"`
+				Value float64 `json:"value"`
+			} `json:"values"`
+		} `json:"snowFallAmount"`
+		SnowLevel struct {
+			Values []struct {
+				Time  string  `json:"validTime"`
+				Value float64 `json:"value"`
+			} `json:"values"`
+		} `json:"snowLevel"`
+	} `json:"properties"`
+}
+
+// FetchFuture resolves lat/lon to its covering NWS gridpoint, then fetches
+// that gridpoint's forecast. elevationM is unused: NWS's own gridpoint
+// forecast already reports a snowLevel series directly.
+func (p *NWSProvider) FetchFuture(ctx context.Context, lat, lon, elevationM float64) ([]HourForecast, error) {
+	url, err := gridpointURL(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := getURL(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching NWS gridpoint forecast")
+	}
+
+	return parseGridpointForecast(data)
+}
+
+// parseGridpointForecast decodes a NWS gridpoint forecast response and
+// expands its snowFallAmount/snowLevel/temperature series into an
+// HourForecast per hour.
+func parseGridpointForecast(data []byte) ([]HourForecast, error) {
+	forecast := &gridpointForecast{}
+	if err := json.Unmarshal(data, forecast); err != nil {
+		return nil, errors.Wrap(err, "error parsing NWS gridpoint forecast")
+	}
+
+	merged := make(map[time.Time]*HourForecast)
+
+	if err := expandGridpointValues(merged, forecast.Properties.SnowFallAmount.Values, accumulate, func(h *HourForecast, v float64) {
+		h.PredictedSnow = MMToInch(v)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := expandGridpointValues(merged, forecast.Properties.SnowLevel.Values, replicate, func(h *HourForecast, v float64) {
+		h.PredictedSnowLevel = v
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := expandGridpointValues(merged, forecast.Properties.Temperature.Values, replicate, func(h *HourForecast, v float64) {
+		h.PredictedTemp = ToFahrenheit(v)
+	}); err != nil {
+		return nil, err
+	}
+
+	return flatten(merged), nil
+}
+
+// gridpointSpread controls how expandGridpointValues distributes a NWS
+// gridpoint value across the hours its validTime range covers.
+type gridpointSpread int
+
+const (
+	// replicate copies an instantaneous reading (temperature, snow level)
+	// unchanged into every hour the validTime range covers.
+	replicate gridpointSpread = iota
+	// accumulate divides an accumulated total (snowfall amount) evenly
+	// across every hour the validTime range covers.
+	accumulate
+)
+
+// expandGridpointValues walks a NWS gridpoint value series, each of which
+// covers a "validTime/PTnH" range, and spreads it across hourly buckets.
+func expandGridpointValues(merged map[time.Time]*HourForecast, values []struct {
+	Time  string  `json:"validTime"`
+	Value float64 `json:"value"`
+}, spread gridpointSpread, apply func(h *HourForecast, v float64)) error {
+	for _, v := range values {
+		parts := strings.Split(v.Time, "/")
+		if len(parts) != 2 {
+			return errors.Errorf("unexpected validTime format: %s", v.Time)
+		}
+
+		t, err := time.Parse("2006-01-02T15:04:05+00:00", parts[0])
+		if err != nil {
+			return err
+		}
+		t = t.Round(0)
+
+		hourMatch := nwsHourRangeRegex.FindStringSubmatch(parts[1])
+		if hourMatch == nil {
+			continue
+		}
+
+		hours, err := strconv.Atoi(hourMatch[1])
+		if err != nil {
+			return err
+		}
+
+		value := v.Value
+		if spread == accumulate {
+			value /= float64(hours)
+		}
+
+		for h := 0; h < hours; h++ {
+			valueTime := t.Add(time.Hour * time.Duration(h))
+
+			present := merged[valueTime]
+			if present == nil {
+				present = &HourForecast{Hour: valueTime}
+				merged[valueTime] = present
+			}
+			apply(present, value)
+		}
+	}
+
+	return nil
+}
+
+// telemetryData is the NWAC/snowobs station timeseries response.
+type telemetryData struct {
+	Series struct {
+		Stations []struct {
+			Observations struct {
+				DateTime   []time.Time `json:"date_time"`
+				Snow       []float64   `json:"snow_depth"`
+				Temp       []float64   `json:"air_temp"`
+				HourPrecip []float64   `json:"precip_accum_one_hour"`
+			} `json:"OBSERVATIONS"`
+		} `json:"STATION"`
+	} `json:"station_timeseries"`
+}
+
+// FetchPast fetches a NWAC/snowobs station timeseries. station is the full
+// telemetry URL, since that's what the free feed we use requires; from/to
+// are not honored, as the feed only returns its own rolling window.
+func (p *NWSProvider) FetchPast(ctx context.Context, station string, from, to time.Time) ([]HourForecast, error) {
+	data, err := getURL(ctx, station)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching station telemetry")
+	}
+
+	return parseTelemetry(data)
+}
+
+// parseTelemetry decodes a NWAC/snowobs station timeseries response into an
+// HourForecast per observation.
+func parseTelemetry(data []byte) ([]HourForecast, error) {
+	telemetry := &telemetryData{}
+	if err := json.Unmarshal(data, telemetry); err != nil {
+		return nil, errors.Wrap(err, "error parsing station telemetry")
+	}
+
+	if len(telemetry.Series.Stations) == 0 {
+		return nil, errors.Errorf("no stations data")
+	}
+
+	observations := telemetry.Series.Stations[0].Observations
+	hours := make([]HourForecast, 0, len(observations.DateTime))
+	for i := range observations.DateTime {
+		// telemetry is taken at the top of the hour and represents what
+		// happened in the previous hour
+		hour := observations.DateTime[i].Add(-time.Hour)
+		hours = append(hours, HourForecast{
+			Hour:         hour,
+			ActualSnow:   observations.Snow[i],
+			ActualTemp:   observations.Temp[i],
+			ActualPrecip: observations.HourPrecip[i],
+		})
+	}
+
+	return hours, nil
+}
+
+func flatten(merged map[time.Time]*HourForecast) []HourForecast {
+	hours := make([]HourForecast, 0, len(merged))
+	for _, h := range merged {
+		hours = append(hours, *h)
+	}
+	return hours
+}
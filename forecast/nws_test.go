@@ -0,0 +1,163 @@
+package forecast
+
+import (
+	"io/ioutil"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestExpandGridpointValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   struct {
+			Time  string  `json:"validTime"`
+			Value float64 `json:"value"`
+		}
+		spread    gridpointSpread
+		wantHours int
+		wantEach  float64
+	}{
+		{
+			name: "single hour span",
+			value: struct {
+				Time  string  `json:"validTime"`
+				Value float64 `json:"value"`
+			}{Time: "2024-02-14T08:00:00+00:00/PT1H", Value: 10},
+			spread:    accumulate,
+			wantHours: 1,
+			wantEach:  10,
+		},
+		{
+			name: "accumulated multi-hour span splits evenly",
+			value: struct {
+				Time  string  `json:"validTime"`
+				Value float64 `json:"value"`
+			}{Time: "2024-02-14T08:00:00+00:00/PT4H", Value: 8},
+			spread:    accumulate,
+			wantHours: 4,
+			wantEach:  2,
+		},
+		{
+			name: "replicated multi-hour span repeats unchanged",
+			value: struct {
+				Time  string  `json:"validTime"`
+				Value float64 `json:"value"`
+			}{Time: "2024-02-14T08:00:00+00:00/PT6H", Value: -5},
+			spread:    replicate,
+			wantHours: 6,
+			wantEach:  -5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := make(map[time.Time]*HourForecast)
+			values := []struct {
+				Time  string  `json:"validTime"`
+				Value float64 `json:"value"`
+			}{tt.value}
+
+			if err := expandGridpointValues(merged, values, tt.spread, func(h *HourForecast, v float64) {
+				h.PredictedTemp = v
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(merged) != tt.wantHours {
+				t.Fatalf("got %d hours, want %d", len(merged), tt.wantHours)
+			}
+
+			for _, h := range merged {
+				if !almostEqual(h.PredictedTemp, tt.wantEach) {
+					t.Errorf("got %f, want %f", h.PredictedTemp, tt.wantEach)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGridpointForecast(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/nws_gridpoint.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseGridpointForecast(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Slice(got, func(i, j int) bool { return got[i].Hour.Before(got[j].Hour) })
+
+	// the fixture's single PT2H snowFallAmount (an accumulated total) is
+	// split evenly across both hours it covers, but snowLevel/temperature
+	// are instantaneous readings NWS repeats unchanged across the range.
+	want := []HourForecast{
+		{
+			Hour:               mustParseNWSHour(t, "2024-02-14T08:00:00+00:00"),
+			PredictedSnow:      MMToInch(10.0),
+			PredictedSnowLevel: 900.0,
+			PredictedTemp:      ToFahrenheit(-5.0),
+		},
+		{
+			Hour:               mustParseNWSHour(t, "2024-02-14T09:00:00+00:00"),
+			PredictedSnow:      MMToInch(10.0),
+			PredictedSnowLevel: 900.0,
+			PredictedTemp:      ToFahrenheit(-5.0),
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d hours, want %d", len(got), len(want))
+	}
+	for i := range got {
+		assertHourForecast(t, i, got[i], want[i])
+	}
+}
+
+func TestParseTelemetry(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/nws_telemetry.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseTelemetry(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []HourForecast{
+		{
+			// telemetry is taken at the top of the hour and represents
+			// what happened in the previous hour
+			Hour:         mustParseNWSHour(t, "2024-02-14T08:00:00+00:00"),
+			ActualSnow:   12.0,
+			ActualTemp:   28.0,
+			ActualPrecip: 0.0,
+		},
+		{
+			Hour:         mustParseNWSHour(t, "2024-02-14T09:00:00+00:00"),
+			ActualSnow:   12.5,
+			ActualTemp:   27.5,
+			ActualPrecip: 0.1,
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d hours, want %d", len(got), len(want))
+	}
+	for i := range got {
+		assertHourForecast(t, i, got[i], want[i])
+	}
+}
+
+func mustParseNWSHour(t *testing.T, s string) time.Time {
+	t.Helper()
+
+	hour, err := time.Parse("2006-01-02T15:04:05+00:00", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hour
+}
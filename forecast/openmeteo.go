@@ -0,0 +1,102 @@
+package forecast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OpenMeteoProvider fetches forecast and historical data from the free,
+// no-API-key-required Open-Meteo APIs.
+type OpenMeteoProvider struct{}
+
+// NewOpenMeteoProvider returns a Provider backed by open-meteo.com.
+func NewOpenMeteoProvider() *OpenMeteoProvider {
+	return &OpenMeteoProvider{}
+}
+
+// openMeteoHourly is the shared shape of the "hourly" block returned by both
+// the forecast and historical archive endpoints.
+type openMeteoHourly struct {
+	Hourly struct {
+		Time                []string  `json:"time"`
+		Temperature2m       []float64 `json:"temperature_2m"`
+		Precipitation       []float64 `json:"precipitation"`
+		Snowfall            []float64 `json:"snowfall"`
+		SnowDepth           []float64 `json:"snow_depth"`
+		FreezingLevelHeight []float64 `json:"freezing_level_height"`
+	} `json:"hourly"`
+}
+
+// FetchFuture fetches the hourly forecast for lat/lon from Open-Meteo.
+// elevationM is unused: Open-Meteo reports a freezing_level_height series
+// directly, so there's no surface reading to estimate one from.
+func (p *OpenMeteoProvider) FetchFuture(ctx context.Context, lat, lon, elevationM float64) ([]HourForecast, error) {
+	url := fmt.Sprintf(
+		"https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&hourly=temperature_2m,precipitation,snowfall,snow_depth,freezing_level_height&daily=sunrise,sunset&temperature_unit=celsius&precipitation_unit=mm&timezone=auto",
+		lat, lon,
+	)
+
+	data, err := getURL(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching Open-Meteo forecast")
+	}
+
+	return parseOpenMeteoHourly(data, true)
+}
+
+// FetchPast fetches historical observations for lat/lon from Open-Meteo's
+// archive API. Open-Meteo addresses by lat/lon, not station id, so station
+// must be passed as "lat,lon".
+func (p *OpenMeteoProvider) FetchPast(ctx context.Context, station string, from, to time.Time) ([]HourForecast, error) {
+	var lat, lon float64
+	if _, err := fmt.Sscanf(station, "%f,%f", &lat, &lon); err != nil {
+		return nil, errors.Wrap(err, "station must be \"lat,lon\" for the Open-Meteo provider")
+	}
+
+	url := fmt.Sprintf(
+		"https://archive-api.open-meteo.com/v1/archive?latitude=%f&longitude=%f&start_date=%s&end_date=%s&hourly=temperature_2m,precipitation,snowfall,snow_depth&temperature_unit=celsius&precipitation_unit=mm&timezone=auto",
+		lat, lon, from.Format("2006-01-02"), to.Format("2006-01-02"),
+	)
+
+	data, err := getURL(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching Open-Meteo archive")
+	}
+
+	return parseOpenMeteoHourly(data, false)
+}
+
+func parseOpenMeteoHourly(data []byte, future bool) ([]HourForecast, error) {
+	parsed := &openMeteoHourly{}
+	if err := json.Unmarshal(data, parsed); err != nil {
+		return nil, errors.Wrap(err, "error parsing Open-Meteo response")
+	}
+
+	hours := make([]HourForecast, 0, len(parsed.Hourly.Time))
+	for i, t := range parsed.Hourly.Time {
+		hour, err := time.Parse("2006-01-02T15:04", t)
+		if err != nil {
+			return nil, err
+		}
+
+		h := HourForecast{Hour: hour}
+		if future {
+			h.PredictedTemp = ToFahrenheit(parsed.Hourly.Temperature2m[i])
+			h.PredictedSnow = MMToInch(parsed.Hourly.Snowfall[i] * 10) // Open-Meteo reports snowfall in cm
+			if i < len(parsed.Hourly.FreezingLevelHeight) {
+				h.PredictedSnowLevel = parsed.Hourly.FreezingLevelHeight[i]
+			}
+		} else {
+			h.ActualTemp = ToFahrenheit(parsed.Hourly.Temperature2m[i])
+			h.ActualPrecip = MMToInch(parsed.Hourly.Precipitation[i])
+			h.ActualSnow = MMToInch(parsed.Hourly.SnowDepth[i] * 1000) // Open-Meteo reports snow_depth in meters
+		}
+		hours = append(hours, h)
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,87 @@
+package forecast
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseOpenMeteoHourly(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		future bool
+		want   []HourForecast
+	}{
+		{
+			name:   "forecast",
+			path:   "testdata/openmeteo_forecast.json",
+			future: true,
+			want: []HourForecast{
+				{
+					Hour:               mustParseOpenMeteoHour(t, "2024-02-14T08:00"),
+					PredictedTemp:      ToFahrenheit(-2.0),
+					PredictedSnow:      MMToInch(0.1 * 10),
+					PredictedSnowLevel: 900.0,
+				},
+				{
+					Hour:               mustParseOpenMeteoHour(t, "2024-02-14T09:00"),
+					PredictedTemp:      ToFahrenheit(-1.0),
+					PredictedSnow:      MMToInch(0.2 * 10),
+					PredictedSnowLevel: 950.0,
+				},
+			},
+		},
+		{
+			name:   "archive",
+			path:   "testdata/openmeteo_archive.json",
+			future: false,
+			want: []HourForecast{
+				{
+					Hour:         mustParseOpenMeteoHour(t, "2024-02-13T08:00"),
+					ActualTemp:   ToFahrenheit(-3.0),
+					ActualPrecip: MMToInch(1.0),
+					ActualSnow:   MMToInch(0.28 * 1000),
+				},
+				{
+					Hour:         mustParseOpenMeteoHour(t, "2024-02-13T09:00"),
+					ActualTemp:   ToFahrenheit(-2.5),
+					ActualPrecip: MMToInch(0.0),
+					ActualSnow:   MMToInch(0.28 * 1000),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := parseOpenMeteoHourly(data, tt.future)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d hours, want %d", len(got), len(tt.want))
+			}
+
+			for i := range got {
+				assertHourForecast(t, i, got[i], tt.want[i])
+			}
+		})
+	}
+}
+
+func mustParseOpenMeteoHour(t *testing.T, s string) time.Time {
+	t.Helper()
+
+	hour, err := time.Parse("2006-01-02T15:04", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hour
+}
@@ -0,0 +1,136 @@
+package forecast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OpenWeatherMapProvider fetches forecast and historical data from the
+// OpenWeatherMap One Call API, which requires an API key.
+type OpenWeatherMapProvider struct {
+	apiKey string
+}
+
+// NewOpenWeatherMapProvider returns a Provider backed by openweathermap.org,
+// authenticated with apiKey.
+func NewOpenWeatherMapProvider(apiKey string) *OpenWeatherMapProvider {
+	return &OpenWeatherMapProvider{apiKey: apiKey}
+}
+
+type openWeatherMapHour struct {
+	Dt   int64   `json:"dt"`
+	Temp float64 `json:"temp"`
+	Rain struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+	Snow struct {
+		OneHour float64 `json:"1h"`
+	} `json:"snow"`
+}
+
+type openWeatherMapOneCall struct {
+	Hourly []openWeatherMapHour `json:"hourly"`
+}
+
+// FetchFuture fetches the hourly forecast for lat/lon from OpenWeatherMap's
+// One Call API. The One Call API doesn't report a freezing level the way
+// NWS and Open-Meteo do, so elevationM is used to estimate one from each
+// hour's surface temperature instead (see EstimateSnowLevelM).
+func (p *OpenWeatherMapProvider) FetchFuture(ctx context.Context, lat, lon, elevationM float64) ([]HourForecast, error) {
+	url := fmt.Sprintf(
+		"https://api.openweathermap.org/data/3.0/onecall?lat=%f&lon=%f&units=metric&exclude=current,minutely,daily,alerts&appid=%s",
+		lat, lon, p.apiKey,
+	)
+
+	data, err := getURL(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching OpenWeatherMap forecast")
+	}
+
+	hours, _, err := parseOpenWeatherMapHourly(data, elevationM, 0, true)
+	return hours, err
+}
+
+// FetchPast fetches historical hourly data for lat/lon from OpenWeatherMap's
+// timemachine endpoint, one day at a time between from and to.
+// OpenWeatherMap addresses history by lat/lon, not station id, so station
+// must be passed as "lat,lon".
+//
+// OpenWeatherMap doesn't report a station snow depth the way NWS telemetry
+// does, only new snowfall per hour, so ActualSnow is a running total of
+// that snowfall accumulated across the fetched window rather than an
+// absolute sensor reading: it starts at 0 at from, not at the snowpack's
+// true depth.
+func (p *OpenWeatherMapProvider) FetchPast(ctx context.Context, station string, from, to time.Time) ([]HourForecast, error) {
+	lat, lon, err := parseLatLonFromStation(station)
+	if err != nil {
+		return nil, err
+	}
+
+	var hours []HourForecast
+	depth := 0.0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		url := fmt.Sprintf(
+			"https://api.openweathermap.org/data/3.0/onecall/timemachine?lat=%f&lon=%f&dt=%d&units=metric&appid=%s",
+			lat, lon, day.Unix(), p.apiKey,
+		)
+
+		data, err := getURL(ctx, url)
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching OpenWeatherMap history")
+		}
+
+		var dayHours []HourForecast
+		dayHours, depth, err = parseOpenWeatherMapHourly(data, 0, depth, false)
+		if err != nil {
+			return nil, err
+		}
+		hours = append(hours, dayHours...)
+	}
+
+	return hours, nil
+}
+
+// parseOpenWeatherMapHourly converts a decoded One Call response's hourly
+// block into HourForecasts. For a future response it estimates a snow
+// level from elevationM; for a past response it adds each hour's new
+// snowfall onto startDepth and returns the accumulated depth, so a caller
+// fetching day-by-day (FetchPast) can carry a continuous running total
+// across calls instead of resetting it each day.
+func parseOpenWeatherMapHourly(data []byte, elevationM, startDepth float64, future bool) ([]HourForecast, float64, error) {
+	parsed := &openWeatherMapOneCall{}
+	if err := json.Unmarshal(data, parsed); err != nil {
+		return nil, startDepth, errors.Wrap(err, "error parsing OpenWeatherMap response")
+	}
+
+	hours := make([]HourForecast, 0, len(parsed.Hourly))
+	depth := startDepth
+	for _, hour := range parsed.Hourly {
+		h := HourForecast{Hour: time.Unix(hour.Dt, 0)}
+		if future {
+			h.PredictedTemp = ToFahrenheit(hour.Temp)
+			h.PredictedSnow = MMToInch(hour.Snow.OneHour)
+			h.PredictedSnowLevel = EstimateSnowLevelM(hour.Temp, elevationM)
+		} else {
+			depth += MMToInch(hour.Snow.OneHour)
+			h.ActualTemp = ToFahrenheit(hour.Temp)
+			h.ActualPrecip = MMToInch(hour.Rain.OneHour)
+			h.ActualSnow = depth
+		}
+		hours = append(hours, h)
+	}
+
+	return hours, depth, nil
+}
+
+func parseLatLonFromStation(station string) (float64, float64, error) {
+	var lat, lon float64
+	if _, err := fmt.Sscanf(station, "%f,%f", &lat, &lon); err != nil {
+		return 0, 0, errors.Wrap(err, "station must be \"lat,lon\" for the OpenWeatherMap provider")
+	}
+	return lat, lon, nil
+}
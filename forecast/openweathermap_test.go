@@ -0,0 +1,82 @@
+package forecast
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseOpenWeatherMapHourly(t *testing.T) {
+	t.Run("future", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/openweathermap_forecast.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, _, err := parseOpenWeatherMapHourly(data, 1200, 0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []HourForecast{
+			{
+				Hour:               time.Unix(1707897600, 0),
+				PredictedTemp:      ToFahrenheit(-2.0),
+				PredictedSnow:      MMToInch(1.0),
+				PredictedSnowLevel: 1200 + (-2.0)/standardLapseRateCPerM,
+			},
+			{
+				Hour:               time.Unix(1707901200, 0),
+				PredictedTemp:      ToFahrenheit(0.65),
+				PredictedSnow:      MMToInch(2.0),
+				PredictedSnowLevel: 1300,
+			},
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("got %d hours, want %d", len(got), len(want))
+		}
+		for i := range got {
+			assertHourForecast(t, i, got[i], want[i])
+		}
+	})
+
+	t.Run("past accumulates a running depth across calls", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/openweathermap_past.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		startDepth := MMToInch(3.0) // as if a prior day's fetch already left 3mm on the ground
+		got, endDepth, err := parseOpenWeatherMapHourly(data, 0, startDepth, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []HourForecast{
+			{
+				Hour:         time.Unix(1707811200, 0),
+				ActualTemp:   ToFahrenheit(-3.0),
+				ActualPrecip: MMToInch(0),
+				ActualSnow:   MMToInch(3.0 + 1.0),
+			},
+			{
+				Hour:         time.Unix(1707814800, 0),
+				ActualTemp:   ToFahrenheit(-2.5),
+				ActualPrecip: MMToInch(0.5),
+				ActualSnow:   MMToInch(3.0 + 1.0), // no new snow this hour
+			},
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("got %d hours, want %d", len(got), len(want))
+		}
+		for i := range got {
+			assertHourForecast(t, i, got[i], want[i])
+		}
+
+		if !almostEqual(endDepth, MMToInch(4.0)) {
+			t.Errorf("got ending depth %f, want %f", endDepth, MMToInch(4.0))
+		}
+	})
+}
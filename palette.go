@@ -0,0 +1,75 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/nicpottier/weatherstrip/config"
+)
+
+// snowPalette holds the parsed colors a strip draws with, falling back to
+// built-in defaults for any field a strip's config leaves unset. All of
+// those defaults except night are weatherstrip's original colors.
+type snowPalette struct {
+	main       *color.RGBA
+	sun        *color.RGBA
+	background *color.RGBA
+	time       *color.RGBA
+	cold       *color.RGBA
+	hot        *color.RGBA
+	night      *color.RGBA
+
+	// twilightTint isn't part of a strip's config; it's a fixed color used
+	// to shade civil-twilight columns, and doesn't follow a configured main.
+	twilightTint *color.RGBA
+}
+
+func newSnowPalette(p config.Palette) *snowPalette {
+	return &snowPalette{
+		main:         colorOrDefault(p.Main, &color.RGBA{128, 255, 255, 255}),
+		sun:          colorOrDefault(p.Sun, &color.RGBA{168, 255, 0, 255}),
+		background:   colorOrDefault(p.Background, &color.RGBA{0, 0, 0, 255}),
+		time:         colorOrDefault(p.Time, &color.RGBA{0, 128, 128, 255}),
+		cold:         colorOrDefault(p.Cold, &color.RGBA{50, 168, 168, 255}),
+		hot:          colorOrDefault(p.Hot, &color.RGBA{139, 168, 50, 255}),
+		night:        colorOrDefault(p.Night, &color.RGBA{40, 90, 90, 255}),
+		twilightTint: &color.RGBA{90, 200, 200, 255},
+	}
+}
+
+func colorOrDefault(hex string, fallback *color.RGBA) *color.RGBA {
+	if hex == "" {
+		return fallback
+	}
+
+	parsed, err := config.ParseColor(hex)
+	if err != nil {
+		log.Printf("invalid palette color %q, using default: %v", hex, err)
+		return fallback
+	}
+
+	return parsed
+}
+
+// tempColor interpolates between cold and hot as temp moves across
+// [coldTemp, hotTemp], clamping at either end.
+func tempColor(temp, coldTemp, hotTemp float64, cold, hot *color.RGBA) *color.RGBA {
+	if temp <= coldTemp {
+		return cold
+	}
+	if temp >= hotTemp {
+		return hot
+	}
+
+	t := (temp - coldTemp) / (hotTemp - coldTemp)
+	return &color.RGBA{
+		R: lerp8(cold.R, hot.R, t),
+		G: lerp8(cold.G, hot.G, t),
+		B: lerp8(cold.B, hot.B, t),
+		A: 255,
+	}
+}
+
+func lerp8(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t)
+}
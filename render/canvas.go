@@ -0,0 +1,70 @@
+// Package render turns a weatherstrip grid into bytes on the wire, via a
+// pluggable Renderer so the same Canvas can come out as a PNG, an SVG, or a
+// 1-bit/3-color e-paper framebuffer.
+package render
+
+import (
+	"image/color"
+)
+
+// Default grid dimensions and cell geometry, used by strips whose config
+// doesn't override them.
+const (
+	GridWidth   = 64
+	GridHeight  = 16
+	CellSize    = 16
+	CellSpacing = 1
+)
+
+// Canvas is a Width x Height grid of cell colors. It's filled in
+// column-by-column the same way the original image.RGBA was, but keeps
+// colors at cell granularity so a Renderer can choose to rasterize them
+// (PNG, e-paper) or emit them as vector shapes (SVG).
+type Canvas struct {
+	Width      int
+	Height     int
+	Background color.Color
+	cells      [][]color.Color
+}
+
+// NewCanvas returns a width x height Canvas filled with background.
+func NewCanvas(width, height int, background color.Color) *Canvas {
+	c := &Canvas{Width: width, Height: height, Background: background}
+	c.cells = make([][]color.Color, width)
+	for x := range c.cells {
+		c.cells[x] = make([]color.Color, height)
+		for y := range c.cells[x] {
+			c.cells[x][y] = background
+		}
+	}
+	return c
+}
+
+// SetPixel sets the color of a single cell.
+func (c *Canvas) SetPixel(x, y int, col color.Color) {
+	if x < 0 || x >= c.Width || y < 0 || y >= c.Height {
+		return
+	}
+	c.cells[x][y] = col
+}
+
+// At returns the color of a single cell.
+func (c *Canvas) At(x, y int) color.Color {
+	if x < 0 || x >= c.Width || y < 0 || y >= c.Height {
+		return c.Background
+	}
+	return c.cells[x][y]
+}
+
+// SetColumn fills column x from row y down to the bottom of the grid with
+// col. When snowing is true and y is exactly Height, the last row is still
+// given a single pixel so a trace of accumulation always shows.
+func (c *Canvas) SetColumn(x, y int, col color.Color, snowing bool) {
+	for yy := y; yy < c.Height; yy++ {
+		c.SetPixel(x, yy, col)
+	}
+
+	if snowing && y == c.Height {
+		c.SetPixel(x, c.Height-1, col)
+	}
+}
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+var (
+	epdBlack = color.RGBA{0, 0, 0, 255}
+	epdWhite = color.RGBA{255, 255, 255, 255}
+	epdRed   = color.RGBA{255, 0, 0, 255}
+)
+
+// EPaperMode selects which Waveshare-style panel a EPaperRenderer targets.
+type EPaperMode int
+
+const (
+	OneBit EPaperMode = iota
+	ThreeColor
+)
+
+// EPaperRenderer rasterizes a Canvas, Floyd-Steinberg dithers it down to a
+// panel's fixed palette, and packs it into the 1bpp-per-plane framebuffer
+// format Waveshare-style e-paper panels expect, ready to flush over SPI.
+type EPaperRenderer struct {
+	Mode EPaperMode
+}
+
+// Render implements Renderer.
+func (r *EPaperRenderer) Render(c *Canvas) ([]byte, string, error) {
+	img := Rasterize(c)
+
+	if r.Mode == ThreeColor {
+		quantized := PaletteQuantize(img, []color.RGBA{epdBlack, epdWhite, epdRed})
+		return packThreeColor(quantized), "application/octet-stream", nil
+	}
+
+	quantized := PaletteQuantize(img, []color.RGBA{epdBlack, epdWhite})
+	return packOneBit(quantized), "application/octet-stream", nil
+}
+
+// packOneBit packs a black/white image into Waveshare's 1bpp framebuffer
+// format: MSB-first, one bit per pixel, 1 = white, 0 = black.
+func packOneBit(img *image.RGBA) []byte {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	stride := (width + 7) / 8
+	buf := make([]byte, stride*height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, _, _, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			if r>>8 > 127 {
+				buf[y*stride+x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+	}
+
+	return buf
+}
+
+// packThreeColor packs a black/white/red image into two concatenated 1bpp
+// framebuffers, the format Waveshare's 3-color panels expect: the
+// black/white plane first, then the red plane, each bit set meaning "paint
+// this color here".
+func packThreeColor(img *image.RGBA) []byte {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	stride := (width + 7) / 8
+	black := make([]byte, stride*height)
+	red := make([]byte, stride*height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			bit := byte(0x80 >> uint(x%8))
+
+			switch {
+			case r>>8 > 200 && g>>8 < 60 && b>>8 < 60:
+				red[y*stride+x/8] |= bit
+			case r>>8 < 127:
+				black[y*stride+x/8] |= bit
+			default:
+				// leave both planes clear; panel default is white
+			}
+		}
+	}
+
+	return append(black, red...)
+}
@@ -0,0 +1,40 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"time"
+)
+
+// AnimateGIF stitches a sequence of Canvases, each rasterized the same way
+// PNGRenderer does, into a looping animated GIF, one frame per Canvas,
+// shown delay apart. It's how a ?range=24h&step=1h request turns a
+// storm's evolution into a single image instead of one request per hour.
+func AnimateGIF(canvases []*Canvas, delay time.Duration) ([]byte, error) {
+	g := &gif.GIF{}
+
+	delayCentis := int(delay / (10 * time.Millisecond))
+	if delayCentis <= 0 {
+		delayCentis = 100
+	}
+
+	for _, c := range canvases {
+		img := Rasterize(c)
+
+		paletted := image.NewPaletted(img.Bounds(), palette.WebSafe)
+		draw.Draw(paletted, paletted.Bounds(), img, image.Point{}, draw.Src)
+
+		g.Image = append(g.Image, paletted)
+		g.Delay = append(g.Delay, delayCentis)
+	}
+
+	buff := &bytes.Buffer{}
+	if err := gif.EncodeAll(buff, g); err != nil {
+		return nil, err
+	}
+
+	return buff.Bytes(), nil
+}
@@ -0,0 +1,77 @@
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+// PaletteQuantize maps every pixel of img onto the nearest color in
+// palette, using Floyd-Steinberg error diffusion so the limited palette
+// (e.g. a 1-bit or 3-color e-paper panel) still reads as shading rather
+// than flat blocks.
+func PaletteQuantize(img *image.RGBA, palette []color.RGBA) *image.RGBA {
+	bounds := img.Bounds()
+	out := image.NewRGBA(bounds)
+
+	// working buffer of float error-accumulated color, so diffusion can
+	// push fractional error into neighboring pixels without clamping early
+	type rgb struct{ r, g, b float64 }
+	width, height := bounds.Dx(), bounds.Dy()
+	buf := make([][]rgb, height)
+	for y := range buf {
+		buf[y] = make([]rgb, width)
+		for x := range buf[y] {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			buf[y][x] = rgb{float64(r >> 8), float64(g >> 8), float64(b >> 8)}
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			old := buf[y][x]
+			nearest := nearestColor(old.r, old.g, old.b, palette)
+			out.Set(bounds.Min.X+x, bounds.Min.Y+y, nearest)
+
+			errR := old.r - float64(nearest.R)
+			errG := old.g - float64(nearest.G)
+			errB := old.b - float64(nearest.B)
+
+			diffuse := func(dx, dy int, frac float64) {
+				nx, ny := x+dx, y+dy
+				if nx < 0 || nx >= width || ny < 0 || ny >= height {
+					return
+				}
+				buf[ny][nx].r += errR * frac
+				buf[ny][nx].g += errG * frac
+				buf[ny][nx].b += errB * frac
+			}
+
+			// classic Floyd-Steinberg kernel
+			diffuse(1, 0, 7.0/16)
+			diffuse(-1, 1, 3.0/16)
+			diffuse(0, 1, 5.0/16)
+			diffuse(1, 1, 1.0/16)
+		}
+	}
+
+	return out
+}
+
+func nearestColor(r, g, b float64, palette []color.RGBA) color.RGBA {
+	best := palette[0]
+	bestDist := -1.0
+
+	for _, p := range palette {
+		dr := r - float64(p.R)
+		dg := g - float64(p.G)
+		db := b - float64(p.B)
+		dist := dr*dr + dg*dg + db*db
+
+		if bestDist < 0 || dist < bestDist {
+			bestDist = dist
+			best = p
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// PNGRenderer rasterizes a Canvas to a PNG, same layout the strip has
+// always used: each cell becomes a CellSize x CellSize block of pixels,
+// separated by CellSpacing.
+type PNGRenderer struct{}
+
+// Render implements Renderer.
+func (r *PNGRenderer) Render(c *Canvas) ([]byte, string, error) {
+	img := Rasterize(c)
+
+	buff := &bytes.Buffer{}
+	if err := png.Encode(buff, img); err != nil {
+		return nil, "", err
+	}
+
+	return buff.Bytes(), "image/png", nil
+}
+
+// Rasterize draws a Canvas to an image.RGBA at full pixel resolution, the
+// same way the strip always has.
+func Rasterize(c *Canvas) *image.RGBA {
+	img := image.NewRGBA(
+		image.Rect(
+			0,
+			c.Height*CellSize+((c.Height+1)*CellSpacing),
+			c.Width*CellSize+((c.Width+1)*CellSpacing),
+			0,
+		),
+	)
+
+	for x := 0; x < img.Bounds().Max.X; x++ {
+		for y := 0; y < img.Bounds().Max.Y; y++ {
+			img.Set(x, y, c.Background)
+		}
+	}
+
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			drawCell(img, x, y, c.At(x, y))
+		}
+	}
+
+	return img
+}
+
+func drawCell(img *image.RGBA, x, y int, col color.Color) {
+	px := 1 + x*CellSize + x*CellSpacing
+	py := 1 + y*CellSize + y*CellSpacing
+
+	for i := 0; i < CellSize; i++ {
+		for j := 0; j < CellSize; j++ {
+			img.Set(px+i, py+j, col)
+		}
+	}
+}
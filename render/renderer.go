@@ -0,0 +1,24 @@
+package render
+
+// Renderer turns a Canvas into the bytes for a particular output format.
+type Renderer interface {
+	// Render encodes the canvas and returns its bytes and MIME type.
+	Render(c *Canvas) ([]byte, string, error)
+}
+
+// ForFormat returns the Renderer for a "?format=" query param value,
+// defaulting to PNG for "" so existing callers keep working unchanged.
+// "epd" targets a 1-bit black/white panel, "epd3" a 3-color black/white/red
+// panel.
+func ForFormat(format string) Renderer {
+	switch format {
+	case "svg":
+		return &SVGRenderer{}
+	case "epd":
+		return &EPaperRenderer{Mode: OneBit}
+	case "epd3":
+		return &EPaperRenderer{Mode: ThreeColor}
+	default:
+		return &PNGRenderer{}
+	}
+}
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// SVGRenderer emits a Canvas as vector rectangles, one per cell, so it
+// scales crisply at any size on a web page instead of being raster-scaled.
+type SVGRenderer struct{}
+
+// Render implements Renderer.
+func (r *SVGRenderer) Render(c *Canvas) ([]byte, string, error) {
+	width := c.Width*CellSize + (c.Width+1)*CellSpacing
+	height := c.Height*CellSize + (c.Height+1)*CellSpacing
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`, width, height, hexColor(c.Background))
+
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			px := 1 + x*CellSize + x*CellSpacing
+			py := 1 + y*CellSize + y*CellSpacing
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, px, py, CellSize, CellSize, hexColor(c.At(x, y)))
+		}
+	}
+
+	b.WriteString(`</svg>`)
+
+	return []byte(b.String()), "image/svg+xml", nil
+}
+
+func hexColor(col color.Color) string {
+	r, g, b, _ := col.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
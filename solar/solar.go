@@ -0,0 +1,135 @@
+// Package solar is a small, dependency-free implementation of the NOAA
+// solar position algorithm, used to figure out sunrise, sunset and civil
+// twilight for a given lat/lon/day so the strip can shade its columns by
+// actual daylight instead of a fixed clock range.
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// zenith angles, in degrees, for the events we care about. Sunrise/sunset
+// is measured at the standard 90.833° (0.833° below the horizon, accounting
+// for atmospheric refraction and the sun's radius); civil twilight extends
+// that to 96°, six degrees below the horizon.
+const (
+	sunriseZenith       = 90.833
+	civilTwilightZenith = 96.0
+)
+
+// Phase describes where a moment in time falls relative to sunrise/sunset
+// and civil twilight.
+type Phase int
+
+const (
+	Night Phase = iota
+	CivilTwilight
+	Day
+)
+
+// Calculator computes sunrise/sunset and civil twilight for a fixed
+// location. Times are returned in loc, defaulting to UTC if loc is nil.
+type Calculator struct {
+	Lat float64
+	Lon float64
+	loc *time.Location
+}
+
+// NewCalculator returns a Calculator for the given coordinates, reporting
+// times in loc.
+func NewCalculator(lat, lon float64, loc *time.Location) *Calculator {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &Calculator{Lat: lat, Lon: lon, loc: loc}
+}
+
+// Sunrise returns the sunrise time for the calendar day containing day.
+func (c *Calculator) Sunrise(day time.Time) (time.Time, bool) {
+	return c.eventTime(day, sunriseZenith, true)
+}
+
+// Sunset returns the sunset time for the calendar day containing day.
+func (c *Calculator) Sunset(day time.Time) (time.Time, bool) {
+	return c.eventTime(day, sunriseZenith, false)
+}
+
+// CivilDawn returns the start of civil twilight (sun 6° below the horizon,
+// rising) for the calendar day containing day.
+func (c *Calculator) CivilDawn(day time.Time) (time.Time, bool) {
+	return c.eventTime(day, civilTwilightZenith, true)
+}
+
+// CivilDusk returns the end of civil twilight (sun 6° below the horizon,
+// setting) for the calendar day containing day.
+func (c *Calculator) CivilDusk(day time.Time) (time.Time, bool) {
+	return c.eventTime(day, civilTwilightZenith, false)
+}
+
+// Phase reports whether t falls in full day, civil twilight, or night for
+// this Calculator's location.
+func (c *Calculator) Phase(t time.Time) Phase {
+	sunrise, hasSunrise := c.Sunrise(t)
+	sunset, hasSunset := c.Sunset(t)
+	dawn, hasDawn := c.CivilDawn(t)
+	dusk, hasDusk := c.CivilDusk(t)
+
+	if hasSunrise && hasSunset && !t.Before(sunrise) && t.Before(sunset) {
+		return Day
+	}
+	if hasDawn && hasDusk && !t.Before(dawn) && t.Before(dusk) {
+		return CivilTwilight
+	}
+	return Night
+}
+
+// eventTime implements the NOAA solar position algorithm for a single
+// sunrise/sunset-style event at the given zenith angle. rising selects
+// sunrise/dawn (true) vs sunset/dusk (false). ok is false for polar
+// day/night, where the sun never crosses zenith that day.
+func (c *Calculator) eventTime(day time.Time, zenithDeg float64, rising bool) (time.Time, bool) {
+	dayOfYear := day.YearDay()
+
+	// solar declination, in degrees, then in radians
+	decl := 23.45 * math.Sin(toRadians(360.0/365.0*float64(dayOfYear+284)))
+	declRad := toRadians(decl)
+
+	latRad := toRadians(c.Lat)
+	zenithRad := toRadians(zenithDeg)
+
+	cosH := (math.Cos(zenithRad) - math.Sin(latRad)*math.Sin(declRad)) / (math.Cos(latRad) * math.Cos(declRad))
+	if cosH < -1 || cosH > 1 {
+		// sun never reaches this zenith angle today (polar day or night)
+		return time.Time{}, false
+	}
+
+	hourAngle := toDegrees(math.Acos(cosH)) / 15.0
+
+	// equation of time correction, in minutes, approximated from day of year
+	b := toRadians(360.0 / 364.0 * float64(dayOfYear-81))
+	eqTime := 9.87*math.Sin(2*b) - 7.53*math.Cos(b) - 1.5*math.Sin(b)
+
+	// solar noon in UTC hours, corrected for longitude and equation of time
+	solarNoon := 12.0 - c.Lon/15.0 - eqTime/60.0
+
+	var eventUTC float64
+	if rising {
+		eventUTC = solarNoon - hourAngle
+	} else {
+		eventUTC = solarNoon + hourAngle
+	}
+
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	event := midnight.Add(time.Duration(eventUTC * float64(time.Hour)))
+
+	return event.In(c.loc), true
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -1,91 +1,93 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"image"
-	"image/color"
-	"image/png"
 	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
-	"regexp"
 	"sort"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/pkg/errors"
-)
-
-const (
-	gridWidth   = 64
-	gridHeight  = 16
-	cellSize    = 16
-	cellSpacing = 1
-	snowlevel   = 1490 // 1490m, base of Tye Mill
-
-	coldTemp = 29 // anything less than this is nice powder
-	hotTemp  = 32 // anything more than this is rain
-
-	// wsdot station
-	wsdotTelemetryURL = "https://www.nwac.us/weatherdata/stevenshwy2/now/"
-
-	// brooks station
-	brooksTelemetryURL = "https://api.snowobs.com/v1/station/timeseries?token=71ad26d7aaf410e39efe91bd414d32e1db5d&stid=50&source=nwac"
 
-	telemetryURL = brooksTelemetryURL
+	"github.com/nicpottier/weatherstrip/archive"
+	"github.com/nicpottier/weatherstrip/config"
+	"github.com/nicpottier/weatherstrip/fetch"
+	"github.com/nicpottier/weatherstrip/forecast"
+	"github.com/nicpottier/weatherstrip/render"
+	"github.com/nicpottier/weatherstrip/solar"
 )
 
-var (
-	mainColor = &color.RGBA{128, 255, 255, 255}
+var la *time.Location
 
-	sunColor = &color.RGBA{168, 255, 0, 255}
+func init() {
+	la, _ = time.LoadLocation("America/Los_Angeles")
+}
 
-	backgroundColor      = &color.RGBA{0, 0, 0, 255}
-	pastSnowDayColor     = mainColor
-	pastSnowNightColor   = mainColor
-	futureSnowDayColor   = mainColor
-	futureSnowNightColor = mainColor
-	timeColor            = &color.RGBA{0, 128, 128, 255}
-	flakeColor           = mainColor
-	nowColor             = &color.RGBA{64, 192, 255, 255}
+// configStore caches the parsed WEATHERSTRIP_CONFIG file for the life of a
+// warm Lambda container, reloading it if its mtime changes.
+var configStore = config.NewStore(os.Getenv("WEATHERSTRIP_CONFIG"))
+
+// liveWindow is how far behind the actual wall clock a requested `now` can
+// be before buildCanvas treats it as a time-travel request rather than a
+// live one: fetching fresh upstream data instead of reconstructing it from
+// an archived snapshot.
+const liveWindow = 2 * time.Hour
+
+// forecastHorizon bounds how far into the future an `at`/`range` request can
+// reach, matching how far out our providers ever actually forecast. Without
+// this a caller could ask for a date decades out and send buildCanvas's
+// start-search loop through maxStartSearchHours of hours looking for data
+// that will never exist.
+const forecastHorizon = 7 * 24 * time.Hour
+
+// maxStartSearchHours caps how many hours buildCanvas will walk forward
+// looking for the first merged hour at/after 4pm the day before now. It's a
+// backstop for sparse or empty merged data, not a value we expect to hit in
+// practice.
+const maxStartSearchHours = 24 * 14
+
+// maxAnimationFrames caps how many frames buildAnimation will render for a
+// single request, so a caller can't ask for e.g. range=10y&step=1m and tie up
+// a Lambda invocation (and its provider fetches) rendering thousands of
+// frames.
+const maxAnimationFrames = 240
 
-	coldColor = &color.RGBA{50, 168, 168, 255}
-	hotColor  = &color.RGBA{139, 168, 50, 255}
+var (
+	archiverOnce sync.Once
+	archiver     *archive.Store
 )
 
-var tempColors = map[int]*color.RGBA{
-	29: &color.RGBA{50, 168, 0, 255},
-	30: &color.RGBA{50, 168, 119, 255},
-	31: &color.RGBA{50, 158, 58, 255},
-	32: &color.RGBA{98, 168, 50, 255},
-}
-
-type HourForecast struct {
-	Hour time.Time `json:"hour"`
-
-	PredictedSnow      float64 `json:"predicted_snow,omitempty"`
-	PredictedSnowLevel float64 `json:"predicted_snow_level,omitempty"`
-	PredictedTemp      float64 `json:"predicted_temp,omitempty"`
+// archiverFromEnv builds the shared archive.Store, backed by an S3 bucket
+// when ARCHIVE_BUCKET is set, the same way fetch's S3 cache tier is
+// configured from FETCH_CACHE_BUCKET. It returns nil when unset, disabling
+// time-travel rendering rather than failing requests that don't use it.
+func archiverFromEnv() *archive.Store {
+	archiverOnce.Do(func() {
+		bucket := os.Getenv("ARCHIVE_BUCKET")
+		if bucket == "" {
+			return
+		}
 
-	ActualSnow   float64 `json:"actual_snow,omitempty"`
-	ActualTemp   float64 `json:"actual_temp,omitempty"`
-	ActualPrecip float64 `json:"actual_precip,omitempty"`
-}
+		store, err := fetch.NewS3Store(bucket)
+		if err != nil {
+			log.Printf("error configuring ARCHIVE_BUCKET: %v", err)
+			return
+		}
 
-var la *time.Location
+		archiver = archive.NewStore(store)
+	})
 
-func init() {
-	la, _ = time.LoadLocation("America/Los_Angeles")
+	return archiver
 }
 
-func dumpData(merged map[time.Time]*HourForecast) {
+func dumpData(merged map[time.Time]*forecast.HourForecast) {
 	// get all our times
 	times := make([]time.Time, 0, len(merged))
 	for t := range merged {
@@ -96,7 +98,7 @@ func dumpData(merged map[time.Time]*HourForecast) {
 	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
 
 	// build a sorted list of our forecasts
-	forecasts := make([]*HourForecast, len(merged))
+	forecasts := make([]*forecast.HourForecast, len(merged))
 
 	// dump in sorted order
 	for i, t := range times {
@@ -107,270 +109,210 @@ func dumpData(merged map[time.Time]*HourForecast) {
 	fmt.Println(string(dumped))
 }
 
-type TelemetryData struct {
-	Series struct {
-		Stations []struct {
-			Observations struct {
-				DateTime   []time.Time `json:"date_time"`
-				Snow24     []float64   `json:"snow_depth_24h"`
-				Snow       []float64   `json:"snow_depth"`
-				Temp       []float64   `json:"air_temp"`
-				HourPrecip []float64   `json:"precip_accum_one_hour"`
-			} `json:"OBSERVATIONS"`
-		} `json:"STATION"`
-	} `json:"station_timeseries"`
+// stripNameFromPath pulls "Stevens" out of a request path like
+// "/Stevens.png".
+func stripNameFromPath(path string) string {
+	name := strings.TrimPrefix(path, "/")
+	return strings.TrimSuffix(name, ".png")
 }
 
-func loadPastTelemetry(merged map[time.Time]*HourForecast, data []byte) error {
-	telemetry := &TelemetryData{}
-	err := json.Unmarshal(data, telemetry)
+func handler(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	cfg, err := configStore.Get()
 	if err != nil {
-		return err
-	}
-
-	if len(telemetry.Series.Stations) == 0 {
-		return errors.Errorf("no stations data")
-	}
-
-	observations := telemetry.Series.Stations[0].Observations
-	for i := range observations.DateTime {
-		forecast := &HourForecast{
-			Hour:         observations.DateTime[i].In(la),
-			ActualSnow:   observations.Snow[i],
-			ActualTemp:   observations.Temp[i],
-			ActualPrecip: observations.HourPrecip[i],
-		}
-
-		// subtract one hour from our forecast hour, telemetry data is taken at the top of the hour and represents
-		// what happened in the previous hour
-		forecast.Hour = forecast.Hour.Add(-time.Minute * 60)
-		merged[forecast.Hour] = forecast
+		log.Fatal(err)
 	}
 
-	return nil
-}
+	name := stripNameFromPath(request.Path)
 
-func loadFuture(merged map[time.Time]*HourForecast, data []byte) error {
-	forecast := Forecast{}
-	err := json.Unmarshal(data, &forecast)
+	strip, err := cfg.Strip(name)
 	if err != nil {
-		return err
+		return events.APIGatewayProxyResponse{StatusCode: 404, Body: err.Error()}, nil
 	}
 
-	regex := regexp.MustCompile("PT(\\d+)H")
-
-	for _, v := range forecast.Properties.SnowFallAmount.Values {
-		// split on /
-		parts := strings.Split(v.Time, "/")
-
-		// Mon Jan 2 15:04:05 MST 2006
-		t, err := time.ParseInLocation("2006-01-02T15:04:05+00:00", parts[0], la)
-		if err != nil {
-			return err
-		}
-		t = t.Round(0)
-		in := toInch(v.Value)
-
-		// figure out range this represents
-		hourMatch := regex.FindAllStringSubmatch(parts[1], 1)
-		if len(hourMatch) == 0 {
-			log.Printf("unable to find range for: %s\n", parts[1])
-			continue
-		}
-
-		hours, err := strconv.Atoi(hourMatch[0][1])
+	now := time.Now().In(la)
+	if raw := request.QueryStringParameters["at"]; raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
 		if err != nil {
-			return err
+			return events.APIGatewayProxyResponse{StatusCode: 400, Body: fmt.Sprintf("invalid at: %s", err)}, nil
 		}
-
-		for h := 0; h < hours; h++ {
-			valueTime := t.Add(time.Hour * time.Duration(h))
-			value := in / float64(hours)
-
-			present := merged[valueTime]
-			if present == nil {
-				merged[valueTime] = &HourForecast{
-					Hour:          valueTime,
-					PredictedSnow: value,
-				}
-			} else {
-				present.PredictedSnow = value
-			}
+		if at.After(time.Now().Add(forecastHorizon)) {
+			return events.APIGatewayProxyResponse{StatusCode: 400, Body: fmt.Sprintf("at is beyond the %s forecast horizon", forecastHorizon)}, nil
 		}
+		now = at.In(la)
 	}
 
-	for _, v := range forecast.Properties.SnowLevel.Values {
-		// split on /
-		parts := strings.Split(v.Time, "/")
-
-		// Mon Jan 2 15:04:05 MST 2006
-		t, err := time.ParseInLocation("2006-01-02T15:04:05+00:00", parts[0], la)
-		if err != nil {
-			return err
-		}
-		t = t.Round(0)
+	if rng := request.QueryStringParameters["range"]; rng != "" {
+		return buildAnimation(name, strip, now, rng, request.QueryStringParameters["step"])
+	}
 
-		// figure out range this represents
-		hourMatch := regex.FindAllStringSubmatch(parts[1], 1)
-		if len(hourMatch) == 0 {
-			log.Printf("unable to find range for: %s\n", parts[1])
-			continue
-		}
+	canvas, err := buildCanvas(name, strip, now)
+	if err != nil {
+		return events.APIGatewayProxyResponse{StatusCode: 404, Body: err.Error()}, nil
+	}
 
-		hours, err := strconv.Atoi(hourMatch[0][1])
-		if err != nil {
-			return err
-		}
+	renderer := render.ForFormat(request.QueryStringParameters["format"])
+	data, contentType, err := renderer.Render(canvas)
+	if err != nil {
+		log.Fatal(err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
 
-		for h := 0; h < hours; h++ {
-			valueTime := t.Add(time.Hour * time.Duration(h))
+	return events.APIGatewayProxyResponse{
+		StatusCode:      200,
+		Body:            encoded,
+		Headers:         map[string]string{"Content-Type": contentType},
+		IsBase64Encoded: true,
+	}, nil
+}
 
-			present := merged[valueTime]
-			if present == nil {
-				merged[valueTime] = &HourForecast{
-					Hour:               valueTime,
-					PredictedSnowLevel: v.Value,
-				}
-			} else {
-				present.PredictedSnowLevel = v.Value
-			}
-		}
+// buildAnimation renders a strip once every step across the range hours
+// leading up to now and stitches the frames into an animated GIF, so
+// ?range=24h&step=1h shows a storm's evolution as one image.
+func buildAnimation(name string, strip *config.Strip, now time.Time, rng, stepParam string) (events.APIGatewayProxyResponse, error) {
+	span, err := time.ParseDuration(rng)
+	if err != nil || span <= 0 {
+		return events.APIGatewayProxyResponse{StatusCode: 400, Body: fmt.Sprintf("invalid range: %s", rng)}, nil
 	}
 
-	for _, v := range forecast.Properties.Temperature.Values {
-		// split on /
-		parts := strings.Split(v.Time, "/")
-
-		// Mon Jan 2 15:04:05 MST 2006
-		t, err := time.ParseInLocation("2006-01-02T15:04:05+00:00", parts[0], la)
-		if err != nil {
-			return err
+	step := time.Hour
+	if stepParam != "" {
+		step, err = time.ParseDuration(stepParam)
+		if err != nil || step <= 0 {
+			return events.APIGatewayProxyResponse{StatusCode: 400, Body: fmt.Sprintf("invalid step: %s", stepParam)}, nil
 		}
-		t = t.Round(0)
+	}
 
-		// figure out range this represents
-		hourMatch := regex.FindAllStringSubmatch(parts[1], 1)
-		if len(hourMatch) == 0 {
-			log.Printf("unable to find range for: %s\n", parts[1])
-			continue
-		}
+	if frames := int(span/step) + 1; frames > maxAnimationFrames {
+		return events.APIGatewayProxyResponse{StatusCode: 400, Body: fmt.Sprintf("range/step would render %d frames, more than the %d allowed", frames, maxAnimationFrames)}, nil
+	}
 
-		hours, err := strconv.Atoi(hourMatch[0][1])
+	var canvases []*render.Canvas
+	for t := now.Add(-span); !t.After(now); t = t.Add(step) {
+		canvas, err := buildCanvas(name, strip, t)
 		if err != nil {
-			return err
-		}
-
-		value := toFahrenheit(v.Value)
-
-		for h := 0; h < hours; h++ {
-			valueTime := t.Add(time.Hour * time.Duration(h))
-
-			present := merged[valueTime]
-			if present == nil {
-				merged[valueTime] = &HourForecast{
-					Hour:          valueTime,
-					PredictedTemp: value,
-				}
-			} else {
-				present.PredictedTemp = value
-			}
+			return events.APIGatewayProxyResponse{StatusCode: 404, Body: err.Error()}, nil
 		}
+		canvases = append(canvases, canvas)
 	}
 
-	return nil
-}
-
-func loadURLData(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+	data, err := render.AnimateGIF(canvases, step)
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	return ioutil.ReadAll(resp.Body)
-}
-
-func handler(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	img := buildImage()
-	buff := &bytes.Buffer{}
-	if err := png.Encode(buff, img); err != nil {
 		log.Fatal(err)
 	}
-	encoded := base64.StdEncoding.EncodeToString(buff.Bytes())
 
 	return events.APIGatewayProxyResponse{
 		StatusCode:      200,
-		Body:            encoded,
-		Headers:         map[string]string{"Content-Type": "image/png"},
+		Body:            base64.StdEncoding.EncodeToString(data),
+		Headers:         map[string]string{"Content-Type": "image/gif"},
 		IsBase64Encoded: true,
 	}, nil
 }
 
-func buildImage() *image.RGBA {
-	merged := make(map[time.Time]*HourForecast)
-
-	now := time.Now().In(la)
+// loadMerged returns the merged forecast/telemetry timeline to render
+// strip from at now. For a live request (now within liveWindow of the
+// actual time) it fetches fresh data from provider and, if an archiver is
+// configured, archives the result for later time-travel requests. For a
+// historical request it requires a previously archived snapshot, since
+// that's the only way to recover what was predicted at the time rather
+// than just what actually happened; telemetry alone can't stand in for
+// that without silently relabeling "what happened" as "what we predicted".
+func loadMerged(ctx context.Context, name string, strip *config.Strip, provider forecast.Provider, now time.Time) (map[time.Time]*forecast.HourForecast, error) {
+	historical := time.Since(now) > liveWindow
+
+	if historical {
+		a := archiverFromEnv()
+		if a == nil {
+			return nil, fmt.Errorf("no archiver configured, can't reconstruct %s as it looked at %s", name, now)
+		}
+		merged, err := a.Load(ctx, name, now)
+		if err != nil {
+			return nil, fmt.Errorf("no archived snapshot for %s at %s: %w", name, now, err)
+		}
+		return merged, nil
+	}
 
-	//url := fmt.Sprintf(telemetryURL, now.AddDate(0, 0, -1).Format("200601021504"), now.Format("200601021504"))
-	//fmt.Println(url)
+	merged := make(map[time.Time]*forecast.HourForecast)
 
-	// scrape the stevens data
-	telemetryData, err := loadURLData(telemetryURL)
+	// fetch recent station telemetry
+	past, err := provider.FetchPast(ctx, strip.TelemetryURL, now.AddDate(0, 0, -1), now)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	forecast.Merge(merged, past)
 
-	err = loadPastTelemetry(merged, telemetryData)
+	// fetch the forecast for our station's location
+	future, err := provider.FetchFuture(ctx, strip.Lat, strip.Lon, strip.ElevationM)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	forecast.Merge(merged, future)
+
+	if a := archiverFromEnv(); a != nil {
+		if err := a.Save(ctx, name, now, merged); err != nil {
+			log.Printf("error archiving %s at %s: %v", name, now, err)
+		}
 	}
 
-	// read our telemetry file
-	//var past []byte
-	//data, err = loadURLData("https://www.nwac.us/data-portal/csv/location/stevens-pass/sensortype/snow_depth/start-date/2018-11-22/end-date/2020-05-23/")
-	//if err != nil {
-	// log.Fatal(err)
-	//}
-	//past = data
-
-	//err = loadPast(merged, past)
-	//if err != nil {
-	//	log.Fatal(err)
-	//}
-
-	// read our forecast data
-	var future []byte
-	data, err := loadURLData("https://api.weather.gov/gridpoints/SEW/164,65")
+	return merged, nil
+}
+
+// buildCanvas renders strip as it appeared at now: "now" rather than an
+// implicit time.Now() so callers can ask for a past moment (via the `at`
+// query param / CLI flag) and get back the strip as it actually looked
+// then, not as it looks today. It returns an error (rather than crashing
+// the process) for conditions ordinary requests can trigger, such as an
+// unarchived historical hour.
+func buildCanvas(name string, strip *config.Strip, now time.Time) (*render.Canvas, error) {
+	colors := newSnowPalette(strip.Palette)
+
+	provider, err := forecast.NewProviderFromEnv()
 	if err != nil {
 		log.Fatal(err)
 	}
-	future = data
 
-	err = loadFuture(merged, future)
+	solarCalc := solar.NewCalculator(strip.Lat, strip.Lon, la)
+
+	ctx := context.Background()
+
+	merged, err := loadMerged(ctx, name, strip, provider, now)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	// print our data out
 	dumpData(merged)
 
 	// start is at 4pm the previous day
-	now = time.Now().Truncate(time.Hour).In(la)
+	now = now.Truncate(time.Hour)
 	yesterday := now.AddDate(0, 0, -1)
 	start := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 16, 0, 0, 0, la)
 
-	for merged[start] == nil {
+	for i := 0; merged[start] == nil; i++ {
+		if i >= maxStartSearchHours {
+			return nil, fmt.Errorf("no merged data found within %d hours of %s for %s", maxStartSearchHours, start, name)
+		}
 		start = start.Add(time.Hour)
 	}
 
 	// when our graph actually starts
 	graphStart := now.Add(time.Hour * -4)
 
-	// end is 64 hours in the future
-	graphEnd := graphStart.Add(time.Hour * time.Duration(64))
+	width := strip.GridWidth
+	if width == 0 {
+		width = render.GridWidth
+	}
+
+	// end is width hours after graphStart
+	graphEnd := graphStart.Add(time.Hour * time.Duration(width))
 
 	curr := start
 
-	img := makeImage()
+	height := strip.GridHeight
+	if height == 0 {
+		height = render.GridHeight
+	}
+
+	canvas := render.NewCanvas(width, height, colors.background)
 
 	// from start to end
 	total := float64(0)
@@ -403,18 +345,11 @@ func buildImage() *image.RGBA {
 	for ; curr.Before(graphEnd); curr = curr.Add(time.Hour) {
 		offset := int(curr.Sub(graphStart) / time.Hour)
 
-		//if curr.Equal(now) {
-		//	setPixel(img, offset, 0, timeColor)
-		//	setPixel(img, offset, 1, timeColor)
-		//	setPixel(img, offset, 2, timeColor)
-		//	setPixel(img, offset, 3, timeColor)
-		//}
-
 		if curr.Hour() == 0 {
-			setPixel(img, offset, 14, timeColor)
-			setPixel(img, offset, 15, timeColor)
+			canvas.SetPixel(offset, height-2, colors.time)
+			canvas.SetPixel(offset, height-1, colors.time)
 		} else if curr.Hour() == 12 {
-			setPixel(img, offset, 15, timeColor)
+			canvas.SetPixel(offset, height-1, colors.time)
 		}
 
 		// we reset accumulation at 4pm
@@ -448,20 +383,20 @@ func buildImage() *image.RGBA {
 				total = 0
 			}
 
-			if forecast.PredictedSnowLevel < snowlevel {
+			if forecast.PredictedSnowLevel < strip.SnowLevelM {
 				if forecast.PredictedSnow > 0 {
 					top := 1 + (offset%2)*2
 
 					if forecast.PredictedSnow > 0 {
-						setPixel(img, offset, top, flakeColor)
+						canvas.SetPixel(offset, top, colors.main)
 					}
 
 					if forecast.PredictedSnow > .25 {
-						setPixel(img, offset, top+4, flakeColor)
+						canvas.SetPixel(offset, top+4, colors.main)
 					}
 
 					if forecast.PredictedSnow > .50 {
-						setPixel(img, offset, top+8, flakeColor)
+						canvas.SetPixel(offset, top+8, colors.main)
 					}
 				}
 
@@ -469,29 +404,32 @@ func buildImage() *image.RGBA {
 			} else {
 				top := 1 + (offset%2)*3
 				if forecast.PredictedSnow > 0 {
-					setPixel(img, offset, top, flakeColor)
-					setPixel(img, offset, top+1, flakeColor)
+					canvas.SetPixel(offset, top, colors.main)
+					canvas.SetPixel(offset, top+1, colors.main)
 				}
 			}
 
-			color := futureSnowDayColor
-			if curr.Hour() >= 16 || curr.Hour() < 9 {
-				color = futureSnowNightColor
+			snowColor := colors.main
+			switch solarCalc.Phase(curr) {
+			case solar.CivilTwilight:
+				snowColor = colors.twilightTint
+			case solar.Night:
+				snowColor = colors.night
 			}
 
 			fmt.Printf("future snow: %s\t%f\t%f\t%f\t%f\n", curr, total, forecast.PredictedSnow, forecast.PredictedSnowLevel, forecast.PredictedTemp)
-			setColumn(img, offset, 16-int(total), color, false)
+			canvas.SetColumn(offset, height-int(total), snowColor, false)
 		} else {
 			temp = forecast.ActualTemp
 
 			if merged[curr].ActualPrecip > 0 {
-				if merged[curr].ActualTemp > hotTemp {
+				if merged[curr].ActualTemp > strip.HotTempF {
 					top := 1 + (offset%2)*3
-					setPixel(img, offset, top, flakeColor)
-					setPixel(img, offset, top+1, flakeColor)
+					canvas.SetPixel(offset, top, colors.main)
+					canvas.SetPixel(offset, top+1, colors.main)
 				} else {
 					top := 1 + (offset%2)*2
-					setPixel(img, offset, top, flakeColor)
+					canvas.SetPixel(offset, top, colors.main)
 				}
 			}
 
@@ -502,118 +440,90 @@ func buildImage() *image.RGBA {
 			if forecast.ActualSnow-startDepth > total {
 				total = forecast.ActualSnow - startDepth
 			}
-			color := pastSnowDayColor
-			if curr.Hour() >= 16 || curr.Hour() < 9 {
-				color = pastSnowNightColor
+
+			snowColor := colors.main
+			switch solarCalc.Phase(curr) {
+			case solar.CivilTwilight:
+				snowColor = colors.twilightTint
+			case solar.Night:
+				snowColor = colors.night
 			}
+
 			fmt.Printf("  past snow: %s\t%f\t%f\t%f\t%f\n", curr, total, forecast.ActualSnow, startDepth, forecast.ActualTemp)
-			setColumn(img, offset, 16-int(total), color, false)
+			canvas.SetColumn(offset, height-int(total), snowColor, false)
 		}
 
-		// display our temp strip
-		if temp > hotTemp {
-			setPixel(img, offset, 0, hotColor)
-		} else if temp < coldTemp {
-			setPixel(img, offset, 0, coldColor)
-		} else {
-			setPixel(img, offset, 0, tempColors[int(temp)])
+		// mark the actual sunrise/sunset hour with a horizon pixel
+		if isSolarEventHour(solarCalc, curr) {
+			canvas.SetPixel(offset, height-3, colors.sun)
 		}
 
+		// display our temp strip
+		canvas.SetPixel(offset, 0, tempColor(temp, strip.ColdTempF, strip.HotTempF, colors.cold, colors.hot))
+
 		// rewrite our time ticks in case they were written over
 		if curr.Hour() == 0 {
-			setPixel(img, offset, 15, timeColor)
-			setPixel(img, offset, 14, timeColor)
+			canvas.SetPixel(offset, height-1, colors.time)
+			canvas.SetPixel(offset, height-2, colors.time)
 		} else if curr.Hour() == 12 {
-			setPixel(img, offset, 15, timeColor)
+			canvas.SetPixel(offset, height-1, colors.time)
 		}
 	}
 
-	return img
+	return canvas, nil
 }
 
-type Forecast struct {
-	Properties struct {
-		Temperature struct {
-			Values []struct {
-				Time  string  `json:"validTime"`
-				Value float64 `json:"value"`
-			} `json:"values"`
-		} `json:"temperature"`
-		SnowFallAmount struct {
-			Values []struct {
-				Time  string  `json:"validTime"`
-				Value float64 `json:"value"`
-			} `json:"values"`
-		} `json:"snowFallAmount"`
-		SnowLevel struct {
-			Values []struct {
-				Time  string  `json:"validTime"`
-				Value float64 `json:"value"`
-			} `json:"values"`
-		} `json:"snowLevel"`
-	} `json:"properties"`
-}
+// isSolarEventHour reports whether curr is the hour in which sunrise or
+// sunset actually falls, so we can draw a horizon marker there instead of
+// at a fixed hour.
+func isSolarEventHour(calc *solar.Calculator, curr time.Time) bool {
+	hourEnd := curr.Add(time.Hour)
 
-func makeImage() *image.RGBA {
-	img := image.NewRGBA(
-		image.Rect(
-			0,
-			gridHeight*cellSize+((gridHeight+1)*cellSpacing),
-			gridWidth*cellSize+((gridWidth+1)*cellSpacing),
-			0,
-		),
-	)
-
-	for x := 0; x < img.Bounds().Max.X; x++ {
-		for y := 0; y < img.Bounds().Max.Y; y++ {
-			img.Set(x, y, color.Black)
-		}
+	if sunrise, ok := calc.Sunrise(curr); ok && !sunrise.Before(curr) && sunrise.Before(hourEnd) {
+		return true
+	}
+	if sunset, ok := calc.Sunset(curr); ok && !sunset.Before(curr) && sunset.Before(hourEnd) {
+		return true
 	}
 
-	return img
+	return false
 }
 
-func setPixel(img *image.RGBA, x int, y int, c color.Color) {
-	x = 1 + x*cellSize + x*cellSpacing
-	y = 1 + y*cellSize + y*cellSpacing
-
-	for i := 0; i < cellSize; i++ {
-		for j := 0; j < cellSize; j++ {
-			img.Set(x+i, y+j, c)
+func main() {
+	if len(os.Args) >= 2 && os.Args[1] == "test" {
+		stripName := "Stevens"
+		if len(os.Args) >= 3 {
+			stripName = os.Args[2]
 		}
-	}
-}
-
-func setColumn(img *image.RGBA, x int, y int, c color.Color, snowing bool) {
-	for yy := y; yy < gridHeight; yy++ {
-		setPixel(img, x, yy, c)
-	}
 
-	if snowing && y == 16 {
-		setPixel(img, x, 15, c)
-	}
-}
+		now := time.Now().In(la)
+		if len(os.Args) >= 4 {
+			at, err := time.Parse(time.RFC3339, os.Args[3])
+			if err != nil {
+				log.Fatal(err)
+			}
+			now = at.In(la)
+		}
 
-func toFahrenheit(c float64) float64 {
-	return c*9/5 + 32
-}
+		cfg, err := configStore.Get()
+		if err != nil {
+			log.Fatal(err)
+		}
 
-func toInch(mm float64) float64 {
-	return mm / 25.4
-}
+		strip, err := cfg.Strip(stripName)
+		if err != nil {
+			log.Fatal(err)
+		}
 
-func main() {
-	if len(os.Args) == 2 && os.Args[1] == "test" {
-		img := buildImage()
-		f, err := os.Create("weatherstrip.png")
+		canvas, err := buildCanvas(stripName, strip, now)
 		if err != nil {
 			log.Fatal(err)
 		}
-		if err := png.Encode(f, img); err != nil {
-			f.Close()
+		data, _, err := (&render.PNGRenderer{}).Render(canvas)
+		if err != nil {
 			log.Fatal(err)
 		}
-		if err := f.Close(); err != nil {
+		if err := ioutil.WriteFile("weatherstrip.png", data, 0644); err != nil {
 			log.Fatal(err)
 		}
 	} else {